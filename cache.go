@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// cacheEntry pairs a looked-up IPInfo with the time it should expire.
+type cacheEntry struct {
+	info    *IPInfo
+	expires time.Time
+}
+
+// Cached wraps a Provider with an LRU cache keyed by IP, so repeated
+// lookups of the same address within ttl skip the upstream call.
+type Cached struct {
+	provider Provider
+	ttl      time.Duration
+	cache    *lru.Cache[string, cacheEntry]
+}
+
+// NewCached wraps provider with an LRU cache of the given size and TTL.
+func NewCached(provider Provider, size int, ttl time.Duration) *Cached {
+	cache, err := lru.New[string, cacheEntry](size)
+	if err != nil {
+		// Only invalid (non-positive) sizes return an error; fall back to a
+		// single-entry cache rather than panicking on a bad config value.
+		cache, _ = lru.New[string, cacheEntry](1)
+	}
+	return &Cached{provider: provider, ttl: ttl, cache: cache}
+}
+
+func (c *Cached) Name() string { return c.provider.Name() }
+
+func (c *Cached) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	key := ip.String()
+
+	if entry, ok := c.cache.Get(key); ok && time.Now().Before(entry.expires) {
+		cacheHits.Inc()
+		return entry.info, nil
+	}
+	cacheMisses.Inc()
+
+	info, err := c.provider.Lookup(ctx, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.Add(key, cacheEntry{info: info, expires: time.Now().Add(c.ttl)})
+	return info, nil
+}
+
+func (c *Cached) Close() error {
+	if cl, ok := c.provider.(Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
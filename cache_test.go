@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// countingProvider is a Provider double that counts Lookup calls so
+// wrapper behavior (caching, rate limiting) can be asserted without a
+// network dependency.
+type countingProvider struct {
+	calls int
+	info  *IPInfo
+}
+
+func (c *countingProvider) Name() string { return "counting" }
+
+func (c *countingProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	c.calls++
+	return c.info, nil
+}
+
+func TestCachedServesRepeatLookupsFromCache(t *testing.T) {
+	inner := &countingProvider{info: &IPInfo{City: "Cacheville"}}
+	cached := NewCached(inner, 10, time.Minute)
+	ip := net.ParseIP("8.8.8.8")
+
+	for i := 0; i < 3; i++ {
+		info, err := cached.Lookup(context.Background(), ip)
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if info.City != "Cacheville" {
+			t.Errorf("City = %q, want %q", info.City, "Cacheville")
+		}
+	}
+
+	if inner.calls != 1 {
+		t.Errorf("underlying provider called %d times, want 1 (later calls should hit the cache)", inner.calls)
+	}
+}
+
+func TestCachedRefetchesAfterTTLExpiry(t *testing.T) {
+	inner := &countingProvider{info: &IPInfo{City: "Cacheville"}}
+	cached := NewCached(inner, 10, time.Nanosecond)
+	ip := net.ParseIP("8.8.8.8")
+
+	if _, err := cached.Lookup(context.Background(), ip); err != nil {
+		t.Fatalf("first Lookup() error = %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := cached.Lookup(context.Background(), ip); err != nil {
+		t.Fatalf("second Lookup() error = %v", err)
+	}
+
+	if inner.calls != 2 {
+		t.Errorf("underlying provider called %d times, want 2 (TTL should have expired)", inner.calls)
+	}
+}
+
+func TestCachedKeysByAddress(t *testing.T) {
+	inner := &countingProvider{info: &IPInfo{City: "Cacheville"}}
+	cached := NewCached(inner, 10, time.Minute)
+
+	cached.Lookup(context.Background(), net.ParseIP("8.8.8.8"))
+	cached.Lookup(context.Background(), net.ParseIP("1.1.1.1"))
+
+	if inner.calls != 2 {
+		t.Errorf("underlying provider called %d times, want 2 (distinct IPs shouldn't share a cache entry)", inner.calls)
+	}
+}
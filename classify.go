@@ -0,0 +1,111 @@
+package main
+
+import "net"
+
+// IPClass categorizes an IP address by routability/scope, matching the
+// IANA special-purpose address registries for IPv4 and IPv6.
+type IPClass int
+
+const (
+	Public IPClass = iota
+	Loopback
+	Private
+	LinkLocal
+	CGNAT
+	Multicast
+	Reserved
+)
+
+func (c IPClass) String() string {
+	switch c {
+	case Public:
+		return "public"
+	case Loopback:
+		return "loopback"
+	case Private:
+		return "private"
+	case LinkLocal:
+		return "link-local"
+	case CGNAT:
+		return "cgnat"
+	case Multicast:
+		return "multicast"
+	case Reserved:
+		return "reserved"
+	default:
+		return "unknown"
+	}
+}
+
+// classifiedBlocks maps CIDR blocks to their IPClass, checked in order.
+// IPv4-mapped IPv6 addresses are unwrapped to their v4 form before this
+// table is consulted, so only one set of IPv4 entries is needed.
+var classifiedBlocks = []struct {
+	cidr  string
+	class IPClass
+}{
+	// IPv4
+	{"127.0.0.0/8", Loopback},
+	{"10.0.0.0/8", Private},
+	{"172.16.0.0/12", Private},
+	{"192.168.0.0/16", Private},
+	{"169.254.0.0/16", LinkLocal},
+	{"100.64.0.0/10", CGNAT},
+	{"224.0.0.0/4", Multicast},
+	{"198.18.0.0/15", Reserved},   // benchmarking (RFC 2544)
+	{"192.0.2.0/24", Reserved},    // documentation (TEST-NET-1)
+	{"198.51.100.0/24", Reserved}, // documentation (TEST-NET-2)
+	{"203.0.113.0/24", Reserved},  // documentation (TEST-NET-3)
+
+	// IPv6
+	{"::1/128", Loopback},
+	{"fe80::/10", LinkLocal},
+	{"fc00::/7", Private}, // unique local addresses
+	{"ff00::/8", Multicast},
+	{"2001:db8::/32", Reserved}, // documentation
+}
+
+var classifiedNets = mustParseClassifiedBlocks(classifiedBlocks)
+
+func mustParseClassifiedBlocks(blocks []struct {
+	cidr  string
+	class IPClass
+}) []struct {
+	net   *net.IPNet
+	class IPClass
+} {
+	parsed := make([]struct {
+		net   *net.IPNet
+		class IPClass
+	}, len(blocks))
+	for i, b := range blocks {
+		parsed[i].net = mustParseCIDR(b.cidr)
+		parsed[i].class = b.class
+	}
+	return parsed
+}
+
+// classifyIP categorizes ip by scope/routability, unwrapping IPv4-mapped
+// IPv6 addresses (::ffff:a.b.c.d) to their v4 form first.
+func classifyIP(ip net.IP) IPClass {
+	if ip == nil {
+		return Reserved
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	for _, b := range classifiedNets {
+		if b.net.Contains(ip) {
+			return b.class
+		}
+	}
+	return Public
+}
+
+// isPrivateIP reports whether ip is anything other than a publicly
+// routable address (private, loopback, link-local, CGNAT, multicast or
+// otherwise reserved).
+func isPrivateIP(ip string) bool {
+	return classifyIP(net.ParseIP(ip)) != Public
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+func TestClassifyIP(t *testing.T) {
+	tests := []struct {
+		name string
+		ip   string
+		want IPClass
+	}{
+		{"ipv4 loopback", "127.0.0.1", Loopback},
+		{"ipv4 private 10/8", "10.1.2.3", Private},
+		{"ipv4 private 172.16/12", "172.16.0.1", Private},
+		{"ipv4 private 192.168/16", "192.168.1.1", Private},
+		{"ipv4 link-local", "169.254.1.1", LinkLocal},
+		{"ipv4 cgnat", "100.64.0.1", CGNAT},
+		{"ipv4 multicast", "224.0.0.1", Multicast},
+		{"ipv4 benchmark", "198.18.0.1", Reserved},
+		{"ipv4 documentation TEST-NET-1", "192.0.2.1", Reserved},
+		{"ipv4 documentation TEST-NET-2", "198.51.100.1", Reserved},
+		{"ipv4 documentation TEST-NET-3", "203.0.113.1", Reserved},
+		{"ipv4 public", "8.8.8.8", Public},
+		{"ipv4-mapped ipv6 private", "::ffff:10.0.0.1", Private},
+		{"ipv4-mapped ipv6 public", "::ffff:8.8.8.8", Public},
+		{"ipv6 loopback", "::1", Loopback},
+		{"ipv6 link-local", "fe80::1", LinkLocal},
+		{"ipv6 unique local", "fc00::1", Private},
+		{"ipv6 multicast", "ff02::1", Multicast},
+		{"ipv6 documentation", "2001:db8::1", Reserved},
+		{"ipv6 public", "2606:4700:4700::1111", Public},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip := net.ParseIP(tt.ip)
+			if ip == nil {
+				t.Fatalf("net.ParseIP(%q) returned nil", tt.ip)
+			}
+			if got := classifyIP(ip); got != tt.want {
+				t.Errorf("classifyIP(%s) = %s, want %s", tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyIPNil(t *testing.T) {
+	if got := classifyIP(nil); got != Reserved {
+		t.Errorf("classifyIP(nil) = %s, want %s", got, Reserved)
+	}
+}
+
+func TestIsPrivateIP(t *testing.T) {
+	if !isPrivateIP("192.168.1.1") {
+		t.Error("isPrivateIP(192.168.1.1) = false, want true")
+	}
+	if isPrivateIP("8.8.8.8") {
+		t.Error("isPrivateIP(8.8.8.8) = true, want false")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds runtime configuration for the IP-intel provider layer.
+// Everything is sourced from the environment so tokens and other secrets
+// never live in source control.
+type Config struct {
+	ProviderOrder []string
+	IPInfoToken   string
+	IPAPIComToken string
+	MaxMindDBPath string
+
+	CacheSize int
+	CacheTTL  time.Duration
+
+	ProviderTimeout time.Duration
+	ProviderQPS     map[string]float64
+
+	TrustedProxies []*net.IPNet
+
+	LogFormat string // "json" or "text"
+}
+
+// LoadConfig reads the provider configuration from the environment,
+// falling back to sane defaults for anything unset.
+func LoadConfig() Config {
+	return Config{
+		ProviderOrder:   splitCSV(getenv("CHECKIP_PROVIDERS", "ipinfo,ipapico,ipapicom,maxmind")),
+		IPInfoToken:     os.Getenv("CHECKIP_IPINFO_TOKEN"),
+		IPAPIComToken:   os.Getenv("CHECKIP_IPAPICOM_TOKEN"),
+		MaxMindDBPath:   os.Getenv("CHECKIP_MAXMIND_DB"),
+		CacheSize:       getenvInt("CHECKIP_CACHE_SIZE", 1024),
+		CacheTTL:        getenvDuration("CHECKIP_CACHE_TTL", 10*time.Minute),
+		ProviderTimeout: getenvDuration("CHECKIP_PROVIDER_TIMEOUT", 3*time.Second),
+		ProviderQPS: map[string]float64{
+			"ipinfo":   getenvFloat("CHECKIP_IPINFO_QPS", 10),
+			"ipapico":  getenvFloat("CHECKIP_IPAPICO_QPS", 2),
+			"ipapicom": getenvFloat("CHECKIP_IPAPICOM_QPS", 2),
+			"maxmind":  getenvFloat("CHECKIP_MAXMIND_QPS", 1000),
+		},
+		TrustedProxies: parseCIDRList(getenv("CHECKIP_TRUSTED_PROXIES", "127.0.0.0/8,::1/128")),
+		LogFormat:      getenv("CHECKIP_LOG_FORMAT", "text"),
+	}
+}
+
+// parseCIDRList parses a comma-separated list of CIDRs, skipping and
+// logging any entry that doesn't parse instead of failing startup.
+func parseCIDRList(csv string) []*net.IPNet {
+	var blocks []*net.IPNet
+	for _, cidr := range splitCSV(csv) {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			slog.Warn("ignoring invalid trusted proxy CIDR", "cidr", cidr, "error", err)
+			continue
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getenvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func getenvFloat(key string, def float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+func getenvDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+func splitCSV(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
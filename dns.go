@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// unresolvableTLDs are reserved/special-use domain suffixes that can never
+// resolve to a real address (RFC 2606, RFC 8375, RFC 3172). Rejecting
+// them up front means they never reach the resolver.
+var unresolvableTLDs = []string{
+	".invalid",
+	".local",
+	".home.arpa",
+	".in-addr.arpa",
+	".ip6.arpa",
+}
+
+func isUnresolvableHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, tld := range unresolvableTLDs {
+		if strings.HasSuffix(host, tld) {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupResult is one resolved address's entry in a /lookup/{host}
+// response, reusing the same IPResponse schema as the rest of the API.
+type LookupResult struct {
+	Host string `json:"host"`
+	IPResponse
+}
+
+// lookupHandler resolves a hostname and returns intel for each of its
+// addresses. To guard against SSRF via attacker-controlled hostnames,
+// reserved/private resolved addresses are classified but never handed to
+// an upstream provider.
+func lookupHandler(w http.ResponseWriter, r *http.Request) {
+	host := strings.TrimPrefix(r.URL.Path, "/lookup/")
+	if host == "" {
+		http.Error(w, "missing hostname", http.StatusBadRequest)
+		return
+	}
+
+	if isUnresolvableHost(host) {
+		http.Error(w, "unresolvable hostname", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), providerTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		http.Error(w, "could not resolve hostname: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	results := make([]LookupResult, 0, len(addrs))
+	for _, addr := range addrs {
+		results = append(results, resolveLookupResult(ctx, host, addr.IP))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+// resolveLookupResult classifies ip and, only for publicly routable
+// addresses, enriches the result with an upstream provider lookup.
+func resolveLookupResult(ctx context.Context, host string, ip net.IP) LookupResult {
+	result := LookupResult{Host: host, IPResponse: IPResponse{IP: ip.String()}}
+
+	class := classifyIP(ip)
+	result.Class = class.String()
+	if class != Public {
+		result.IsPrivate = true
+		result.Reserved = class == Reserved
+		return result
+	}
+
+	info, err := ipProvider.Lookup(ctx, ip)
+	if err != nil {
+		slog.Error("getting IP info for lookup", "host", host, "ip", ip.String(), "error", err)
+		return result
+	}
+
+	result.City = info.City
+	result.Region = info.Region
+	result.Country = info.Country
+	result.Loc = info.Loc
+	result.Org = info.Org
+	result.Timezone = info.Timezone
+	return result
+}
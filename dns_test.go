@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestIsUnresolvableHost(t *testing.T) {
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"example.com", false},
+		{"foo.invalid", true},
+		{"printer.local", true},
+		{"router.home.arpa", true},
+		{"1.0.0.10.in-addr.arpa", true},
+		{"1.0.0.0.ip6.arpa", true},
+		{"FOO.LOCAL", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			if got := isUnresolvableHost(tt.host); got != tt.want {
+				t.Errorf("isUnresolvableHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveLookupResultSkipsProviderForPrivateAddresses(t *testing.T) {
+	// A nil ipProvider would panic if called, so this also asserts that
+	// reserved/private addresses never reach the provider layer.
+	ipProvider = nil
+
+	result := resolveLookupResult(context.Background(), "internal.example", net.ParseIP("192.168.1.1"))
+
+	if !result.IsPrivate {
+		t.Error("IsPrivate = false, want true for a private address")
+	}
+	if result.Class != Private.String() {
+		t.Errorf("Class = %q, want %q", result.Class, Private.String())
+	}
+}
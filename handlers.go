@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IPResponse is the stable JSON schema returned by the API surface.
+type IPResponse struct {
+	IP        string `json:"ip"`
+	City      string `json:"city,omitempty"`
+	Region    string `json:"region,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Loc       string `json:"loc,omitempty"`
+	Org       string `json:"org,omitempty"`
+	Timezone  string `json:"timezone,omitempty"`
+	IsPrivate bool   `json:"is_private"`
+	Reserved  bool   `json:"reserved"`
+	Class     string `json:"class,omitempty"`
+}
+
+var htmlTemplate = template.Must(template.New("ip").Parse(`<!DOCTYPE html>
+<html>
+<head><title>checkip</title></head>
+<body>
+<h1>{{.IP}}</h1>
+<ul>
+{{if .IsPrivate}}<li>{{.Class}} address - no location data</li>{{else}}
+<li>City: {{.City}}</li>
+<li>Region: {{.Region}}</li>
+<li>Country: {{.Country}}</li>
+<li>Loc: {{.Loc}}</li>
+<li>Org: {{.Org}}</li>
+<li>Timezone: {{.Timezone}}</li>
+{{end}}
+</ul>
+</body>
+</html>
+`))
+
+// buildIPResponse resolves the caller's IP and assembles the response payload,
+// skipping the upstream lookup for private/reserved addresses.
+func buildIPResponse(r *http.Request) IPResponse {
+	ipAddress := getIPAddress(r)
+	resp := IPResponse{IP: ipAddress}
+
+	class := classifyIP(net.ParseIP(ipAddress))
+	resp.Class = class.String()
+	if class != Public {
+		resp.IsPrivate = true
+		resp.Reserved = class == Reserved
+		return resp
+	}
+
+	ipInfo, err := getIPInfo(ipAddress)
+	if err != nil {
+		slog.Error("getting IP info", "ip", ipAddress, "error", err)
+		return resp
+	}
+
+	resp.City = ipInfo.City
+	resp.Region = ipInfo.Region
+	resp.Country = ipInfo.Country
+	resp.Loc = ipInfo.Loc
+	resp.Org = ipInfo.Org
+	resp.Timezone = ipInfo.Timezone
+	return resp
+}
+
+// negotiateFormat picks a response format from the ?format= query param,
+// falling back to the Accept header, and finally plain text.
+func negotiateFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/json"):
+		return "json"
+	case strings.Contains(accept, "text/html"):
+		return "html"
+	default:
+		return "text"
+	}
+}
+
+func writeJSON(w http.ResponseWriter, resp IPResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func writeHTML(w http.ResponseWriter, resp IPResponse) {
+	w.Header().Set("Content-Type", "text/html")
+	if err := htmlTemplate.Execute(w, resp); err != nil {
+		slog.Error("rendering HTML response", "error", err)
+	}
+}
+
+func writeText(w http.ResponseWriter, resp IPResponse) {
+	w.Header().Set("Content-Type", "text/plain")
+	if resp.IsPrivate {
+		fmt.Fprintf(w, "Your IP address is: %s\nLocation: N/A (%s address)\nTimezone: N/A (%s address)",
+			resp.IP, resp.Class, resp.Class)
+		return
+	}
+	fmt.Fprintf(w, "Your IP address is: %s\nLocation: %s, %s, %s\nTimezone: %s",
+		resp.IP, resp.City, resp.Region, resp.Country, resp.Timezone)
+}
+
+// ipHandler negotiates the response format and writes the client's IP info.
+func ipHandler(w http.ResponseWriter, r *http.Request) {
+	resp := buildIPResponse(r)
+
+	switch negotiateFormat(r) {
+	case "json":
+		writeJSON(w, resp)
+	case "html":
+		writeHTML(w, resp)
+	default:
+		writeText(w, resp)
+	}
+}
+
+// jsonHandler always returns the full IPResponse as JSON, ipinfo.io-style.
+func jsonHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, buildIPResponse(r))
+}
+
+// fieldHandler returns a handler that writes just one field of the
+// IPResponse as plain text, matching ipinfo-style single-field endpoints.
+func fieldHandler(field func(IPResponse) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := buildIPResponse(r)
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, field(resp))
+	}
+}
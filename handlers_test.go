@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// stubProvider is a Provider double that returns a canned result without
+// making any network calls, so handler tests stay hermetic.
+type stubProvider struct {
+	info *IPInfo
+	err  error
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	return s.info, s.err
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		query  string
+		want   string
+	}{
+		{"query format overrides Accept header", "application/json", "format=html", "html"},
+		{"json Accept header", "application/json", "", "json"},
+		{"html Accept header", "text/html", "", "html"},
+		{"no Accept header defaults to text", "", "", "text"},
+		{"curl-style Accept header defaults to text", "*/*", "", "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := "/"
+			if tt.query != "" {
+				url += "?" + tt.query
+			}
+			req := httptest.NewRequest(http.MethodGet, url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if got := negotiateFormat(req); got != tt.want {
+				t.Errorf("negotiateFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// withTestProviderState swaps the package-level provider/resolver state
+// for the duration of fn and restores it afterwards, since buildIPResponse
+// reads them as globals wired up in main().
+func withTestProviderState(t *testing.T, provider Provider, fn func()) {
+	t.Helper()
+	origResolver, origProvider, origTimeout := remoteIPResolver, ipProvider, providerTimeout
+	remoteIPResolver = NewRemoteIPResolver(nil)
+	ipProvider = provider
+	providerTimeout = time.Second
+	t.Cleanup(func() {
+		remoteIPResolver, ipProvider, providerTimeout = origResolver, origProvider, origTimeout
+	})
+	fn()
+}
+
+func TestBuildIPResponsePrivateAddressSkipsProvider(t *testing.T) {
+	withTestProviderState(t, &stubProvider{err: errors.New("provider must not be called for private IPs")}, func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "192.168.1.10:1234"
+
+		resp := buildIPResponse(req)
+
+		if !resp.IsPrivate {
+			t.Error("IsPrivate = false, want true for a private address")
+		}
+		if resp.Class != Private.String() {
+			t.Errorf("Class = %q, want %q", resp.Class, Private.String())
+		}
+		if resp.City != "" {
+			t.Errorf("City = %q, want empty (provider should not have been called)", resp.City)
+		}
+	})
+}
+
+func TestBuildIPResponsePublicAddressCallsProvider(t *testing.T) {
+	withTestProviderState(t, &stubProvider{info: &IPInfo{City: "Testville", Country: "ZZ"}}, func() {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "8.8.8.8:1234"
+
+		resp := buildIPResponse(req)
+
+		if resp.IsPrivate {
+			t.Error("IsPrivate = true, want false for a public address")
+		}
+		if resp.City != "Testville" || resp.Country != "ZZ" {
+			t.Errorf("got City=%q Country=%q, want City=%q Country=%q", resp.City, resp.Country, "Testville", "ZZ")
+		}
+	})
+}
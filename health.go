@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ready flips to true once a provider probe has succeeded at least once,
+// gating /readyz until the service can actually answer lookups.
+var ready atomic.Bool
+
+// probeIP is a well-known public address used purely to check that at
+// least one configured provider can answer a lookup.
+var probeIP = net.ParseIP("1.1.1.1")
+
+// runReadinessProbe polls the provider chain until a lookup succeeds (or
+// ctx is cancelled at shutdown), then marks the service ready.
+func runReadinessProbe(ctx context.Context, timeout time.Duration) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	probe := func() bool {
+		probeCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		_, err := ipProvider.Lookup(probeCtx, probeIP)
+		if err != nil {
+			slog.Warn("readiness probe failed", "error", err)
+			return false
+		}
+		return true
+	}
+
+	if probe() {
+		ready.Store(true)
+		return
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if probe() {
+				ready.Store(true)
+				return
+			}
+		}
+	}
+}
+
+// healthzHandler is a liveness check: if the process can handle HTTP
+// requests at all, it's alive.
+func healthzHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// readyzHandler is a readiness check: it fails until at least one
+// configured intel provider has answered a probe lookup successfully.
+func readyzHandler(w http.ResponseWriter, r *http.Request) {
+	if !ready.Load() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+)
+
+// newLogger builds the process-wide structured logger. format is "json"
+// for machine-readable output (the usual choice in production) or
+// anything else for slog's human-readable text handler.
+func newLogger(format string) *slog.Logger {
+	if format == "json" {
+		return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	}
+	return slog.New(slog.NewTextHandler(os.Stdout, nil))
+}
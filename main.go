@@ -2,17 +2,15 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // IPInfo JSON response from IPInfo
@@ -25,60 +23,30 @@ type IPInfo struct {
 	Timezone string `json:"timezone"`
 }
 
+// remoteIPResolver resolves the client IP for a request, trusting
+// forwarding headers only from proxies in its configured CIDR set. It is
+// wired up in main() from the process environment.
+var remoteIPResolver *RemoteIPResolver
+
 // getIPAddress extracts the client's IP address from the request.
 func getIPAddress(r *http.Request) string {
-	if xForwardedFor := r.Header.Get("X-Forwarded-For"); xForwardedFor != "" {
-		parts := strings.Split(xForwardedFor, ",")
-		return strings.TrimSpace(parts[0])
-	}
-
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		log.Printf("Error getting IP: %v", err)
-		return ""
-	}
-	return ip
+	return remoteIPResolver.Resolve(r)
 }
 
-// getIPInfo calls the IPInfo API to get information about the IP address.
-func getIPInfo(ip string) (*IPInfo, error) {
-
-	url := fmt.Sprintf("http://ipinfo.io/%s?token=35c09591be32a1", ip)
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var ipInfo IPInfo
-	err = json.Unmarshal(body, &ipInfo)
-	if err != nil {
-		return nil, err
-	}
-	return &ipInfo, nil
-}
-
-// isPrivateIP checks if an IP address is a private address.
-func isPrivateIP(ip string) bool {
-	privateIPBlocks := []*net.IPNet{
-		mustParseCIDR("10.0.0.0/8"),
-		mustParseCIDR("172.16.0.0/12"),
-		mustParseCIDR("192.168.0.0/16"),
-		mustParseCIDR("127.0.0.0/8"),
-	}
+// ipProvider is the configured provider chain used to answer lookups, and
+// providerTimeout bounds each call. Both are wired up in main() from the
+// process environment.
+var (
+	ipProvider      Provider
+	providerTimeout time.Duration
+)
 
-	parsedIP := net.ParseIP(ip)
-	for _, block := range privateIPBlocks {
-		if block.Contains(parsedIP) {
-			return true
-		}
-	}
-	return false
+// getIPInfo looks up information about ip through the configured provider
+// chain, bounded by the per-provider request timeout.
+func getIPInfo(ip string) (*IPInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), providerTimeout)
+	defer cancel()
+	return ipProvider.Lookup(ctx, net.ParseIP(ip))
 }
 
 func mustParseCIDR(cidr string) *net.IPNet {
@@ -86,43 +54,43 @@ func mustParseCIDR(cidr string) *net.IPNet {
 	return block
 }
 
-// ipHandler handles the incoming HTTP request and writes the client's IP info.
-func ipHandler(w http.ResponseWriter, r *http.Request) {
-	ipAddress := getIPAddress(r)
-	log.Printf("Received request from: %s", ipAddress)
-
-	if isPrivateIP(ipAddress) {
-		fmt.Fprintf(w, "Your IP address is: %s\nLocation: N/A (private IP)\nTimezone: N/A (private IP)", ipAddress)
-		return
-	}
-
-	ipInfo, err := getIPInfo(ipAddress)
-	if err != nil {
-		fmt.Fprintf(w, "Error getting IP info: %s", err)
-		return
-	}
-
-	response := fmt.Sprintf("Your IP address is: %s\nLocation: %s, %s, %s\nTimezone: %s",
-		ipAddress, ipInfo.City, ipInfo.Region, ipInfo.Country, ipInfo.Timezone)
-	fmt.Fprint(w, response)
-}
-
 func main() {
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080" // Default port if not specified
 	}
 
-	server := &http.Server{Addr: ":" + port, Handler: nil}
+	cfg := LoadConfig()
+	slog.SetDefault(newLogger(cfg.LogFormat))
+
+	providerTimeout = cfg.ProviderTimeout
+	ipProvider = NewCached(BuildProviderChain(cfg), cfg.CacheSize, cfg.CacheTTL)
+	remoteIPResolver = NewRemoteIPResolver(cfg.TrustedProxies)
+
+	probeCtx, stopProbe := context.WithCancel(context.Background())
+	defer stopProbe()
+	go runReadinessProbe(probeCtx, cfg.ProviderTimeout)
 
-	// Handle routes
-	http.HandleFunc("/", ipHandler)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", ipHandler)
+	mux.HandleFunc("/json", jsonHandler)
+	mux.HandleFunc("/ip", fieldHandler(func(r IPResponse) string { return r.IP }))
+	mux.HandleFunc("/city", fieldHandler(func(r IPResponse) string { return r.City }))
+	mux.HandleFunc("/country", fieldHandler(func(r IPResponse) string { return r.Country }))
+	mux.HandleFunc("/tz", fieldHandler(func(r IPResponse) string { return r.Timezone }))
+	mux.HandleFunc("/lookup/", lookupHandler)
+	mux.HandleFunc("/healthz", healthzHandler)
+	mux.HandleFunc("/readyz", readyzHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	server := &http.Server{Addr: ":" + port, Handler: withMiddleware(mux)}
 
 	// Start server in a goroutine so that it doesn't block
 	go func() {
-		fmt.Println("Starting server on :", port)
+		slog.Info("starting server", "port", port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("ListenAndServe error: %v", err)
+			slog.Error("ListenAndServe error", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -130,13 +98,21 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server...")
+	stopProbe()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatalf("Server forced to shutdown: %v", err)
+		slog.Error("server forced to shutdown", "error", err)
+		os.Exit(1)
+	}
+
+	if cl, ok := ipProvider.(Closer); ok {
+		if err := cl.Close(); err != nil {
+			slog.Error("closing provider chain", "error", err)
+		}
 	}
 
-	log.Println("Server exiting")
+	slog.Info("server exiting")
 }
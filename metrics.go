@@ -0,0 +1,45 @@
+package main
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "checkip_requests_total",
+		Help: "Total HTTP requests served, by route and status code.",
+	}, []string{"route", "status"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "checkip_request_duration_seconds",
+		Help:    "HTTP request latency in seconds, by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route"})
+
+	providerCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "checkip_provider_call_duration_seconds",
+		Help:    "IP-intel provider call latency in seconds, by provider.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"provider"})
+
+	providerCallErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "checkip_provider_call_errors_total",
+		Help: "IP-intel provider call errors, by provider.",
+	}, []string{"provider"})
+
+	cacheHits = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "checkip_cache_hits_total",
+		Help: "Provider cache hits.",
+	})
+
+	cacheMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "checkip_cache_misses_total",
+		Help: "Provider cache misses.",
+	})
+
+	rateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "checkip_rate_limit_rejections_total",
+		Help: "Provider calls rejected by the per-provider rate limiter.",
+	}, []string{"provider"})
+)
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// requestIDCounter generates short, process-unique request IDs without
+// pulling in a UUID dependency for something purely diagnostic.
+var requestIDCounter atomic.Uint64
+
+func newRequestID() string {
+	return fmt.Sprintf("%d-%d", time.Now().UnixNano(), requestIDCounter.Add(1))
+}
+
+// requestIDFromContext returns the request ID stashed by withRequestID, or
+// "" if none is present (e.g. outside of an HTTP request).
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// withRequestID assigns a request ID to every inbound request, propagating
+// it through the request context and echoing it back as a response header.
+func withRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-ID", id)
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// withRecover turns a panic in the handler chain into a 500 response
+// instead of taking down the server.
+func withRecover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				slog.Error("panic recovered", "request_id", requestIDFromContext(r.Context()), "panic", rec)
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lookupRoutePrefix is the path prefix under which the caller supplies an
+// arbitrary hostname (see dns.go's lookupHandler).
+const lookupRoutePrefix = "/lookup/"
+
+// metricsRoute collapses a request path to a bounded-cardinality label
+// for Prometheus. Routes with a path parameter (currently just
+// /lookup/{host}, which takes attacker-controlled hostnames) are
+// templated rather than recorded verbatim, so distinct hostnames don't
+// each mint a new time series. Any other path that isn't one of the
+// exact routes registered in main() is served by the "/" catch-all
+// handler, so it is labeled "/".
+func metricsRoute(path string) string {
+	if strings.HasPrefix(path, lookupRoutePrefix) {
+		return lookupRoutePrefix + ":host"
+	}
+	switch path {
+	case "/json", "/ip", "/city", "/country", "/tz", "/healthz", "/readyz", "/metrics":
+		return path
+	}
+	return "/"
+}
+
+// statusRecorder captures the status code written by a handler so
+// middleware can report it after ServeHTTP returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// withObservability logs each request and records Prometheus request
+// count/latency metrics, labeled by route. The bookkeeping runs from a
+// defer so a panic in next still gets recorded (as a 500) before
+// withRecover, further out in the chain, turns it into a response.
+func withObservability(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		defer func() {
+			status := rec.status
+			p := recover()
+			if p != nil {
+				status = http.StatusInternalServerError
+			}
+
+			duration := time.Since(start)
+			path := r.URL.Path
+			route := metricsRoute(path)
+
+			requestsTotal.WithLabelValues(route, fmt.Sprintf("%d", status)).Inc()
+			requestDuration.WithLabelValues(route).Observe(duration.Seconds())
+
+			slog.Info("request",
+				"request_id", requestIDFromContext(r.Context()),
+				"method", r.Method,
+				"path", path,
+				"status", status,
+				"duration_ms", duration.Milliseconds(),
+			)
+
+			if p != nil {
+				panic(p)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// withMiddleware applies the standard middleware chain to a handler.
+func withMiddleware(h http.Handler) http.Handler {
+	return withRecover(withRequestID(withObservability(h)))
+}
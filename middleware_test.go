@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestMetricsRoute(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "/"},
+		{"/json", "/json"},
+		{"/ip", "/ip"},
+		{"/lookup/example.com", "/lookup/:host"},
+		{"/lookup/attacker-controlled.invalid", "/lookup/:host"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := metricsRoute(tt.path); got != tt.want {
+				t.Errorf("metricsRoute(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
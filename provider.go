@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+)
+
+// Provider looks up IP intelligence for an address from a single backend
+// (a hosted API, an offline database, ...).
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, ip net.IP) (*IPInfo, error)
+}
+
+// Closer is implemented by providers that hold resources (e.g. an open
+// mmdb file) that must be released on shutdown.
+type Closer interface {
+	Close() error
+}
+
+// Chain tries each Provider in order and returns the first successful
+// result, so a hosted API outage or rate limit falls through to the next
+// configured backend instead of failing the request.
+type Chain []Provider
+
+func (c Chain) Name() string { return "chain" }
+
+func (c Chain) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	var lastErr error
+	for _, p := range c {
+		start := time.Now()
+		info, err := p.Lookup(ctx, ip)
+		providerCallDuration.WithLabelValues(p.Name()).Observe(time.Since(start).Seconds())
+		if err == nil {
+			return info, nil
+		}
+		providerCallErrors.WithLabelValues(p.Name()).Inc()
+		lastErr = fmt.Errorf("%s: %w", p.Name(), err)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no providers configured")
+	}
+	return nil, lastErr
+}
+
+func (c Chain) Close() error {
+	for _, p := range c {
+		if cl, ok := p.(Closer); ok {
+			if err := cl.Close(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BuildProviderChain wires up the configured providers, in the configured
+// order, each wrapped with a per-provider rate limiter.
+func BuildProviderChain(cfg Config) Provider {
+	var chain Chain
+	for _, name := range cfg.ProviderOrder {
+		p := newProvider(name, cfg)
+		if p == nil {
+			continue
+		}
+		chain = append(chain, RateLimited(p, cfg.ProviderQPS[name]))
+	}
+	return chain
+}
+
+func newProvider(name string, cfg Config) Provider {
+	switch name {
+	case "ipinfo":
+		return NewIPInfoProvider(cfg.IPInfoToken, cfg.ProviderTimeout)
+	case "ipapico":
+		return NewIPAPICoProvider(cfg.ProviderTimeout)
+	case "ipapicom":
+		return NewIPAPIComProvider(cfg.IPAPIComToken, cfg.ProviderTimeout)
+	case "maxmind":
+		if cfg.MaxMindDBPath == "" {
+			return nil
+		}
+		p, err := NewMaxMindProvider(cfg.MaxMindDBPath)
+		if err != nil {
+			slog.Warn("maxmind provider disabled", "error", err)
+			return nil
+		}
+		return p
+	default:
+		slog.Warn("unknown provider ignored", "provider", name)
+		return nil
+	}
+}
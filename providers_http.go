@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+)
+
+// ipinfoProvider queries ipinfo.io.
+type ipinfoProvider struct {
+	token  string
+	client *http.Client
+}
+
+// NewIPInfoProvider returns a Provider backed by ipinfo.io. token may be
+// empty, in which case ipinfo.io's unauthenticated rate limits apply.
+func NewIPInfoProvider(token string, timeout time.Duration) Provider {
+	return &ipinfoProvider{token: token, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *ipinfoProvider) Name() string { return "ipinfo" }
+
+func (p *ipinfoProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	url := fmt.Sprintf("https://ipinfo.io/%s/json", ip.String())
+	if p.token != "" {
+		url += "?token=" + p.token
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var info IPInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ipapicoProvider queries ipapi.co.
+type ipapicoProvider struct {
+	client *http.Client
+}
+
+func NewIPAPICoProvider(timeout time.Duration) Provider {
+	return &ipapicoProvider{client: &http.Client{Timeout: timeout}}
+}
+
+func (p *ipapicoProvider) Name() string { return "ipapico" }
+
+func (p *ipapicoProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	url := fmt.Sprintf("https://ipapi.co/%s/json/", ip.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw struct {
+		City     string  `json:"city"`
+		Region   string  `json:"region"`
+		Country  string  `json:"country_name"`
+		Org      string  `json:"org"`
+		Timezone string  `json:"timezone"`
+		Lat      float64 `json:"latitude"`
+		Lon      float64 `json:"longitude"`
+		Error    bool    `json:"error"`
+		Reason   string  `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if raw.Error {
+		return nil, fmt.Errorf("ipapi.co: %s", raw.Reason)
+	}
+
+	return &IPInfo{
+		City:     raw.City,
+		Region:   raw.Region,
+		Country:  raw.Country,
+		Org:      raw.Org,
+		Timezone: raw.Timezone,
+		Loc:      fmt.Sprintf("%.4f,%.4f", raw.Lat, raw.Lon),
+	}, nil
+}
+
+// ipapicomProvider queries ip-api.com.
+type ipapicomProvider struct {
+	token  string
+	client *http.Client
+}
+
+func NewIPAPIComProvider(token string, timeout time.Duration) Provider {
+	return &ipapicomProvider{token: token, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *ipapicomProvider) Name() string { return "ipapicom" }
+
+func (p *ipapicomProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	host := "ip-api.com"
+	scheme := "http"
+	if p.token != "" {
+		host = "pro.ip-api.com"
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/json/%s?fields=status,message,city,regionName,country,org,timezone,lat,lon", scheme, host, ip.String())
+	if p.token != "" {
+		url += "&key=" + p.token
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var raw struct {
+		Status   string  `json:"status"`
+		Message  string  `json:"message"`
+		City     string  `json:"city"`
+		Region   string  `json:"regionName"`
+		Country  string  `json:"country"`
+		Org      string  `json:"org"`
+		Timezone string  `json:"timezone"`
+		Lat      float64 `json:"lat"`
+		Lon      float64 `json:"lon"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, err
+	}
+	if raw.Status != "success" {
+		return nil, fmt.Errorf("ip-api.com: %s", raw.Message)
+	}
+
+	return &IPInfo{
+		City:     raw.City,
+		Region:   raw.Region,
+		Country:  raw.Country,
+		Org:      raw.Org,
+		Timezone: raw.Timezone,
+		Loc:      fmt.Sprintf("%.4f,%.4f", raw.Lat, raw.Lon),
+	}, nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// maxmindProvider answers lookups from a local GeoLite2 City mmdb, so the
+// service can run fully offline once a database is provisioned.
+type maxmindProvider struct {
+	db *geoip2.Reader
+}
+
+// NewMaxMindProvider opens the GeoLite2 City database at path.
+func NewMaxMindProvider(path string) (Provider, error) {
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open maxmind db: %w", err)
+	}
+	return &maxmindProvider{db: db}, nil
+}
+
+func (p *maxmindProvider) Name() string { return "maxmind" }
+
+func (p *maxmindProvider) Close() error { return p.db.Close() }
+
+func (p *maxmindProvider) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	record, err := p.db.City(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IPInfo{
+		City:     record.City.Names["en"],
+		Region:   firstSubdivision(record),
+		Country:  record.Country.Names["en"],
+		Loc:      fmt.Sprintf("%.4f,%.4f", record.Location.Latitude, record.Location.Longitude),
+		Timezone: record.Location.TimeZone,
+	}, nil
+}
+
+func firstSubdivision(record *geoip2.City) string {
+	if len(record.Subdivisions) == 0 {
+		return ""
+	}
+	return record.Subdivisions[0].Names["en"]
+}
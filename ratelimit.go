@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimited wraps a Provider with a per-provider QPS limit so a single
+// backend can't be hammered past what its API plan allows.
+type rateLimited struct {
+	provider Provider
+	limiter  *rate.Limiter
+}
+
+// RateLimited wraps provider with a token-bucket limiter allowing qps
+// requests per second (burst of 1). A non-positive qps disables limiting.
+func RateLimited(provider Provider, qps float64) Provider {
+	if qps <= 0 {
+		return provider
+	}
+	return &rateLimited{provider: provider, limiter: rate.NewLimiter(rate.Limit(qps), 1)}
+}
+
+func (r *rateLimited) Name() string { return r.provider.Name() }
+
+func (r *rateLimited) Lookup(ctx context.Context, ip net.IP) (*IPInfo, error) {
+	if !r.limiter.Allow() {
+		rateLimitRejections.WithLabelValues(r.provider.Name()).Inc()
+		return nil, fmt.Errorf("%s: rate limit exceeded", r.provider.Name())
+	}
+	return r.provider.Lookup(ctx, ip)
+}
+
+func (r *rateLimited) Close() error {
+	if cl, ok := r.provider.(Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestRateLimitedRejectsBeyondBurst(t *testing.T) {
+	inner := &countingProvider{info: &IPInfo{}}
+	limited := RateLimited(inner, 1) // 1 QPS, burst of 1
+	ip := net.ParseIP("8.8.8.8")
+
+	if _, err := limited.Lookup(context.Background(), ip); err != nil {
+		t.Fatalf("first call: unexpected error %v", err)
+	}
+	if _, err := limited.Lookup(context.Background(), ip); err == nil {
+		t.Error("second immediate call: expected a rate limit rejection, got nil error")
+	}
+	if inner.calls != 1 {
+		t.Errorf("underlying provider called %d times, want 1 (second call should have been rejected)", inner.calls)
+	}
+}
+
+func TestRateLimitedZeroQPSDisablesLimiting(t *testing.T) {
+	inner := &countingProvider{info: &IPInfo{}}
+	limited := RateLimited(inner, 0)
+	ip := net.ParseIP("8.8.8.8")
+
+	for i := 0; i < 5; i++ {
+		if _, err := limited.Lookup(context.Background(), ip); err != nil {
+			t.Fatalf("call %d: unexpected error %v", i, err)
+		}
+	}
+	if inner.calls != 5 {
+		t.Errorf("underlying provider called %d times, want 5 (qps<=0 should disable limiting)", inner.calls)
+	}
+}
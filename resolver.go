@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RemoteIPResolver determines the real client IP for a request, honoring
+// forwarding headers only when the immediate peer (r.RemoteAddr) is a
+// known, trusted reverse proxy. This prevents a client from spoofing its
+// own address via X-Forwarded-For et al.
+type RemoteIPResolver struct {
+	TrustedProxies []*net.IPNet
+}
+
+// NewRemoteIPResolver builds a resolver that trusts the given proxy CIDRs.
+func NewRemoteIPResolver(trusted []*net.IPNet) *RemoteIPResolver {
+	return &RemoteIPResolver{TrustedProxies: trusted}
+}
+
+// Resolve returns the best-effort client IP for r.
+func (res *RemoteIPResolver) Resolve(r *http.Request) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+
+	if !res.isTrusted(peer) {
+		// The immediate peer isn't a trusted proxy, so any forwarding
+		// headers it sent could be forged by the client itself.
+		return peer
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		if ip := res.resolveForwardedFor(xff); ip != "" {
+			return ip
+		}
+	}
+
+	if forwarded := r.Header.Get("Forwarded"); forwarded != "" {
+		if ip := res.resolveForwarded(forwarded); ip != "" {
+			return ip
+		}
+	}
+
+	for _, header := range []string{"CF-Connecting-IP", "True-Client-IP", "X-Real-IP"} {
+		if v := r.Header.Get(header); v != "" && validIP(v) {
+			return v
+		}
+	}
+
+	return peer
+}
+
+// resolveForwardedFor walks the X-Forwarded-For chain right-to-left,
+// skipping entries inside a trusted proxy, and returns the first
+// untrusted (i.e. real client) address it finds.
+func (res *RemoteIPResolver) resolveForwardedFor(xff string) string {
+	parts := strings.Split(xff, ",")
+	for i := len(parts) - 1; i >= 0; i-- {
+		candidate := strings.TrimSpace(parts[i])
+		if !validIP(candidate) {
+			continue
+		}
+		if !res.isTrusted(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func (res *RemoteIPResolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, block := range res.TrustedProxies {
+		if block.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedForRE extracts the raw "for=" node token (quoted-string or
+// token, per RFC 7239's ABNF) from a Forwarded header element.
+var forwardedForRE = regexp.MustCompile(`(?i)for=("[^"]*"|[^;,\s]+)`)
+
+// parseForwardedNode reduces an RFC 7239 "for=" node to a bare IP,
+// stripping the optional surrounding quotes, IPv6 brackets, and a
+// trailing ":port" (node-port is legal on both bracketed IPv6 and plain
+// IPv4 addresses, e.g. "for=203.0.113.60:4089").
+func parseForwardedNode(node string) string {
+	node = strings.Trim(node, `"`)
+
+	if strings.HasPrefix(node, "[") {
+		end := strings.Index(node, "]")
+		if end == -1 {
+			return ""
+		}
+		return node[1:end]
+	}
+
+	if idx := strings.LastIndex(node, ":"); idx != -1 {
+		if host := node[:idx]; validIP(host) {
+			return host
+		}
+	}
+
+	return node
+}
+
+// resolveForwarded walks the comma-separated elements of an RFC 7239
+// Forwarded header right-to-left (closest hop last), skipping entries
+// inside a trusted proxy, and returns the first untrusted "for=" address
+// it finds. This mirrors resolveForwardedFor: a trusted proxy can still
+// pass an attacker-controlled Forwarded header through untouched, so the
+// leading element can't be trusted outright.
+func (res *RemoteIPResolver) resolveForwarded(header string) string {
+	elements := strings.Split(header, ",")
+	for i := len(elements) - 1; i >= 0; i-- {
+		match := forwardedForRE.FindStringSubmatch(elements[i])
+		if match == nil {
+			continue
+		}
+		candidate := parseForwardedNode(match[1])
+		if !validIP(candidate) {
+			continue
+		}
+		if !res.isTrusted(candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func validIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
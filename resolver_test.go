@@ -0,0 +1,119 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func mustCIDR(t *testing.T, cidr string) *net.IPNet {
+	t.Helper()
+	_, block, err := net.ParseCIDR(cidr)
+	if err != nil {
+		t.Fatalf("net.ParseCIDR(%q): %v", cidr, err)
+	}
+	return block
+}
+
+func TestRemoteIPResolverResolve(t *testing.T) {
+	resolver := NewRemoteIPResolver([]*net.IPNet{mustCIDR(t, "10.0.0.0/8")})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{
+			name:       "untrusted peer ignores XFF",
+			remoteAddr: "203.0.113.5:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "203.0.113.5",
+		},
+		{
+			name:       "trusted proxy, single XFF hop is the client",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted proxy, XFF chain skips trusted hops right-to-left",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 10.0.0.2"},
+			want:       "1.2.3.4",
+		},
+		{
+			name:       "trusted proxy falls back to RemoteAddr with no headers",
+			remoteAddr: "10.0.0.1:1234",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "trusted proxy honors X-Real-IP when no XFF/Forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			headers:    map[string]string{"X-Real-IP": "1.2.3.4"},
+			want:       "1.2.3.4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := resolver.Resolve(req); got != tt.want {
+				t.Errorf("Resolve() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveForwardedFor(t *testing.T) {
+	resolver := NewRemoteIPResolver([]*net.IPNet{mustCIDR(t, "10.0.0.0/8")})
+
+	tests := []struct {
+		name string
+		xff  string
+		want string
+	}{
+		{"single untrusted hop", "1.2.3.4", "1.2.3.4"},
+		{"skips trailing trusted hops", "1.2.3.4, 10.0.0.2, 10.0.0.3", "1.2.3.4"},
+		{"all hops trusted", "10.0.0.2, 10.0.0.3", ""},
+		{"ignores invalid entries", "1.2.3.4, not-an-ip", "1.2.3.4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.resolveForwardedFor(tt.xff); got != tt.want {
+				t.Errorf("resolveForwardedFor(%q) = %q, want %q", tt.xff, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveForwarded(t *testing.T) {
+	resolver := NewRemoteIPResolver([]*net.IPNet{mustCIDR(t, "10.0.0.0/8")})
+
+	tests := []struct {
+		name      string
+		forwarded string
+		want      string
+	}{
+		{"single untrusted hop", "for=1.2.3.4", "1.2.3.4"},
+		{"trusted proxy passes attacker for= through untouched", "for=10.0.0.2", ""},
+		{"skips trusted hops right-to-left", "for=1.2.3.4, for=10.0.0.2", "1.2.3.4"},
+		{"quoted IPv6 with port", `for="[2001:db8::1]:1234"`, "2001:db8::1"},
+		{"unbracketed IPv4 with port", "for=203.0.113.60:4089", "203.0.113.60"},
+		{"unbracketed IPv4 with port behind a trusted hop", "for=203.0.113.60:4089, for=10.0.0.2:5555", "203.0.113.60"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolver.resolveForwarded(tt.forwarded); got != tt.want {
+				t.Errorf("resolveForwarded(%q) = %q, want %q", tt.forwarded, got, tt.want)
+			}
+		})
+	}
+}